@@ -0,0 +1,73 @@
+package main
+
+// Trigger thresholds for the active abilities. Tuned conservatively: casting
+// an ability burns its cooldown, so each one only fires when it clearly
+// beats moving.
+const (
+	lightTriggerDistance   = 2
+	yellTriggerDistance    = 1
+	planLowSanityThreshold = 50
+	planTriggerDistance    = 1
+	safeDangerThreshold    = 1.0
+)
+
+type abilityAction int
+
+const (
+	actionNone abilityAction = iota
+	actionLight
+	actionPlan
+	actionYell
+	actionShelter
+)
+
+// decideAbility picks between moving and casting one of the active
+// abilities this turn, given the current danger map and nearby allies. It
+// is the top-level policy: called before move selection, it only yields
+// actionNone when no ability clearly beats moving.
+func decideAbility(g grid, me explorer, explorers []explorer, wanderers []wanderer, dangers [][]float64) abilityAction {
+	meDists := bfsDistances(g, me.coord)
+
+	// An about-to-hit chaser is the most urgent threat: check YELL before
+	// the merely preventive LIGHT, so an adjacent wanderer always gets
+	// stunned rather than lit up.
+	for _, w := range wanderers {
+		if meDists[w.coord.y][w.coord.x] <= yellTriggerDistance {
+			return actionYell
+		}
+	}
+
+	if me.lightCooldown == 0 {
+		for _, w := range wanderers {
+			if meDists[w.coord.y][w.coord.x] <= lightTriggerDistance {
+				return actionLight
+			}
+		}
+	}
+
+	if me.planCooldown == 0 {
+		for _, e := range explorers {
+			if e.id == me.id {
+				continue
+			}
+			if e.sanity < planLowSanityThreshold && meDists[e.coord.y][e.coord.x] <= planTriggerDistance {
+				return actionPlan
+			}
+		}
+	}
+
+	if me.shelterState == 0 && isStandingOnSafeShelter(g, me.coord, dangers) {
+		return actionShelter
+	}
+
+	return actionNone
+}
+
+// isStandingOnSafeShelter reports whether me.coord itself is a shelter cell
+// currently out of danger. SHELTER only protects the explorer casting it
+// from where they stand, so this must not match a shelter one step away —
+// reaching it is the move search's job (see the shelterApproachBonus in
+// sim.go's evaluate).
+func isStandingOnSafeShelter(g grid, from coord, dangers [][]float64) bool {
+	return g[from.y][from.x] == cellShelter && dangers[from.y][from.x] < safeDangerThreshold
+}
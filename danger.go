@@ -0,0 +1,68 @@
+package main
+
+// dangerSource is a single threat radiating danger outward from a cell.
+type dangerSource struct {
+	coord  coord
+	weight float64
+}
+
+func dangerSourcesFromWanderers(wanderers []wanderer) []dangerSource {
+	res := make([]dangerSource, len(wanderers))
+	for i, w := range wanderers {
+		res[i] = dangerSource{w.coord, float64(w.recallTime)}
+	}
+	return res
+}
+
+func dangerSourcesFromSpawningMinions(spawningMinions []spawningMinion) []dangerSource {
+	res := make([]dangerSource, len(spawningMinions))
+	for i, s := range spawningMinions {
+		// The closer spawnTime is to 0, the sooner this minion starts
+		// chasing, so weight it inversely: almost-spawned minions already
+		// radiate danger.
+		res[i] = dangerSource{s.coord, 1.0 / float64(s.spawnTime+1)}
+	}
+	return res
+}
+
+// dangerSourcesFromPredictedPaths turns each wanderer's predictedTargetPath
+// into its own danger sources, one per step, weighted down by how many
+// turns out that step is. This lets a wanderer that's currently far in BFS
+// terms still repel us from the cell it's about to reach.
+func dangerSourcesFromPredictedPaths(wanderers []wanderer, explorers []explorer, g grid) []dangerSource {
+	res := make([]dangerSource, 0, len(wanderers))
+	for _, w := range wanderers {
+		path := predictedTargetPath(w, explorers, g)
+		for turnsOut, c := range path {
+			res = append(res, dangerSource{c, 1.0 / float64(turnsOut+1)})
+		}
+	}
+	return res
+}
+
+// computeDangerMap runs a separate BFS from each source (every grid step
+// costs 1, so BFS gives the same distances Dijkstra would) and, for every
+// reachable cell, adds each source's weight divided by one plus its distance
+// to the cell.
+func computeDangerMap(g grid, sources []dangerSource) [][]float64 {
+	height := len(g)
+	width := len(g[0])
+
+	res := make([][]float64, height)
+	for y := range res {
+		res[y] = make([]float64, width)
+	}
+
+	for _, s := range sources {
+		dists := bfsDistances(g, s.coord)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				if dists[y][x] == infDistance {
+					continue
+				}
+				res[y][x] += s.weight / float64(dists[y][x]+1)
+			}
+		}
+	}
+	return res
+}
@@ -10,15 +10,17 @@ type grid [][]cell
 type cell int
 
 const (
-	inputWall  = "#"
-	inputSpawn = "w"
-	inputEmpty = "."
+	inputWall    = "#"
+	inputSpawn   = "w"
+	inputEmpty   = "."
+	inputShelter = "$"
 )
 
 const (
-	cellWall  = iota
-	cellSpawn = iota
-	cellEmpty = iota
+	cellWall    = iota
+	cellSpawn   = iota
+	cellEmpty   = iota
+	cellShelter = iota
 )
 
 type coord struct {
@@ -33,8 +35,12 @@ const (
 )
 
 type explorer struct {
-	id    int
-	coord coord
+	id            int
+	coord         coord
+	sanity        int
+	planCooldown  int
+	lightCooldown int
+	shelterState  int
 }
 
 type wanderer struct {
@@ -58,7 +64,7 @@ type loggable interface {
 }
 
 func (e explorer) String() string {
-	return fmt.Sprintf("explorer %d %d %d", e.id, e.coord.x, e.coord.y)
+	return fmt.Sprintf("explorer %d %d %d %d %d %d %d", e.id, e.coord.x, e.coord.y, e.sanity, e.planCooldown, e.lightCooldown, e.shelterState)
 }
 
 func (w wanderer) String() string {
@@ -105,6 +111,8 @@ func cellToString(c cell) string {
 		return "w"
 	case cellEmpty:
 		return "."
+	case cellShelter:
+		return "$"
 	default:
 		panic("unrecognized cell " + string(c))
 	}
@@ -118,6 +126,8 @@ func parseCell(c string) cell {
 		return cellSpawn
 	case inputEmpty:
 		return cellEmpty
+	case inputShelter:
+		return cellShelter
 	default:
 		panic("unrecognized string " + c)
 	}
@@ -147,77 +157,37 @@ func sendWait() {
 	send("WAIT")
 }
 
-func abs(n int) int {
-	if n < 0 {
-		return -n
-	}
-	return n
+func sendPlan() {
+	send("PLAN")
 }
 
-func dist(from coord, to coord) int {
-	return abs(to.x-from.x) + abs(to.y-from.y)
+func sendLight() {
+	send("LIGHT")
 }
 
-func getClosestWanderer(from coord, wanderers []wanderer) wanderer {
-	if len(wanderers) == 0 {
-		panic("cannot find closest wanderer if there is no wanderer")
-	}
-	bestIndex := -1
-	bestDistance := -1
-	for i, w := range wanderers {
-		d := dist(w.coord, from)
-		if bestDistance == -1 || d < bestDistance {
-			bestIndex = i
-			bestDistance = d
-		}
-	}
-	return wanderers[bestIndex]
+func sendYell() {
+	send("YELL")
 }
 
-func getEmptyCells(g grid) []coord {
-	res := make([]coord, 0)
-	for i, line := range g {
-		for j, cell := range line {
-			if cell == cellEmpty {
-				res = append(res, coord{j, i})
-			}
-		}
-	}
-	return res
-}
-
-func getCloseEmptyCells(g grid, from coord) []coord {
-	res := make([]coord, 0)
-	for i, line := range g {
-		for j, cell := range line {
-			if cell == cellEmpty && dist(from, coord{j, i}) <= 4 {
-				res = append(res, coord{j, i})
-			}
-		}
-	}
-	return res
+func sendShelter(id int) {
+	send("SHELTER")
+	log(fmt.Sprintf("explorer %d taking shelter", id))
 }
 
-func getFarestCoord(from coord, candidates []coord) coord {
-	if len(candidates) == 0 {
-		panic("no candidates for farest coord")
+// minBFSDistanceTo returns the BFS distance from `from` to the closest coord
+// in `targets`, or infDistance if none is reachable.
+func minBFSDistanceTo(g grid, from coord, targets []coord) int {
+	if len(targets) == 0 {
+		return infDistance
 	}
-	bestIndex := -1
-	bestDistance := -1
-	for i, c := range candidates {
-		d := dist(from, c)
-		if bestDistance == -1 || d > bestDistance {
-			bestIndex = i
-			bestDistance = d
+	dists := bfsDistances(g, from)
+	best := infDistance
+	for _, t := range targets {
+		if dists[t.y][t.x] < best {
+			best = dists[t.y][t.x]
 		}
 	}
-	return candidates[bestIndex]
-}
-
-func getAwayFromClosestWanderer(g grid, me explorer, wanderers []wanderer) coord {
-	closestWanderer := getClosestWanderer(me.coord, wanderers)
-	empties := getCloseEmptyCells(g, me.coord)
-	return getFarestCoord(closestWanderer.coord, empties)
+	return best
 }
 
 func main() {
@@ -240,6 +210,8 @@ func main() {
 	scanner.Scan()
 	fmt.Sscan(scanner.Text(), &sanityLossLonely, &sanityLossGroup, &wandererSpawnTime, &wandererLifeTime)
 
+	prevWanderers := make(map[int]wanderer)
+
 	for {
 		var entityCount int
 		scanner.Scan()
@@ -252,12 +224,14 @@ func main() {
 		for i := 0; i < entityCount; i++ {
 			var entityType string
 			var id, x, y, param0, param1, param2 int
+			var sanity, planCooldown, lightCooldown, shelterState int
 			scanner.Scan()
-			fmt.Sscan(scanner.Text(), &entityType, &id, &x, &y, &param0, &param1, &param2)
+			fmt.Sscan(scanner.Text(), &entityType, &id, &x, &y, &param0, &param1, &param2,
+				&sanity, &planCooldown, &lightCooldown, &shelterState)
 
 			switch entityType {
 			case entityTypeExplorer:
-				explorers = append(explorers, explorer{id, coord{x, y}})
+				explorers = append(explorers, explorer{id, coord{x, y}, sanity, planCooldown, lightCooldown, shelterState})
 			case entityTypeWanderer:
 				state := minionState(param1)
 				switch state {
@@ -279,7 +253,15 @@ func main() {
 
 		for _, w := range wanderers {
 			log(w.String())
+			if prev, ok := prevWanderers[w.id]; ok && (prev.target != w.target || (w.recallTime > prev.recallTime)) {
+				log(fmt.Sprintf("target switch: wanderer %d now chasing explorer %d (was %d)", w.id, w.target, prev.target))
+			}
+		}
+		nextPrevWanderers := make(map[int]wanderer, len(wanderers))
+		for _, w := range wanderers {
+			nextPrevWanderers[w.id] = w
 		}
+		prevWanderers = nextPrevWanderers
 
 		for _, s := range spawningMinions {
 			log(s.String())
@@ -290,11 +272,37 @@ func main() {
 		log("Me :")
 		log(myExplorer.String())
 
-		if len(wanderers) > 0 {
-			away := getAwayFromClosestWanderer(currentGrid, myExplorer, wanderers)
-			sendMove(away.x, away.y)
-		} else {
-			sendWait()
+		dangerSources := append(dangerSourcesFromWanderers(wanderers), dangerSourcesFromSpawningMinions(spawningMinions)...)
+		dangerSources = append(dangerSources, dangerSourcesFromPredictedPaths(wanderers, explorers, currentGrid)...)
+		dangers := computeDangerMap(currentGrid, dangerSources)
+
+		switch decideAbility(currentGrid, myExplorer, explorers, wanderers, dangers) {
+		case actionLight:
+			sendLight()
+		case actionPlan:
+			sendPlan()
+		case actionYell:
+			sendYell()
+		case actionShelter:
+			sendShelter(myExplorer.id)
+		default:
+			state := simState{
+				g:                currentGrid,
+				explorers:        explorers,
+				myID:             myExplorer.id,
+				wanderers:        wanderers,
+				spawningMinions:  spawningMinions,
+				wandererLifeTime: wandererLifeTime,
+				sanityLossLonely: sanityLossLonely,
+				sanityLossGroup:  sanityLossGroup,
+				dangers:          dangers,
+			}
+			best := searchBestMove(state, simMaxDepth)
+			if best == myExplorer.coord {
+				sendWait()
+			} else {
+				sendMove(best.x, best.y)
+			}
 		}
 	}
 }
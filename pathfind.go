@@ -0,0 +1,116 @@
+package main
+
+const infDistance = 1 << 30
+
+func neighbors(g grid, from coord) []coord {
+	height := len(g)
+	width := len(g[0])
+	res := make([]coord, 0, 4)
+	candidates := []coord{
+		{from.x + 1, from.y},
+		{from.x - 1, from.y},
+		{from.x, from.y + 1},
+		{from.x, from.y - 1},
+	}
+	for _, c := range candidates {
+		if c.x < 0 || c.x >= width || c.y < 0 || c.y >= height {
+			continue
+		}
+		if g[c.y][c.x] == cellWall {
+			continue
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// bfsDistanceCache memoizes bfsDistances by origin. The grid is fixed for
+// the lifetime of a game (only entities move, walls never do), so a
+// distance field computed from a given origin is valid for every call that
+// follows, including across turns and across a minimax rollout's many
+// re-derivations of the same few origins.
+var bfsDistanceCache = make(map[coord][][]int)
+
+// bfsDistances computes the shortest-path distance in cells from `from` to
+// every other cell of g, respecting walls. Spawn cells are walkable like
+// empty ones. Unreachable cells are left at infDistance. Results are cached
+// per origin; see bfsDistanceCache.
+func bfsDistances(g grid, from coord) [][]int {
+	if dists, ok := bfsDistanceCache[from]; ok {
+		return dists
+	}
+
+	height := len(g)
+	width := len(g[0])
+
+	dists := make([][]int, height)
+	for y := range dists {
+		dists[y] = make([]int, width)
+		for x := range dists[y] {
+			dists[y][x] = infDistance
+		}
+	}
+
+	dists[from.y][from.x] = 0
+	queue := []coord{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, n := range neighbors(g, current) {
+			if dists[n.y][n.x] != infDistance {
+				continue
+			}
+			dists[n.y][n.x] = dists[current.y][current.x] + 1
+			queue = append(queue, n)
+		}
+	}
+	bfsDistanceCache[from] = dists
+	return dists
+}
+
+// nextStepOn reconstructs the first move of a shortest path from `from` to
+// `to` on g, by walking back from `to` along strictly decreasing distances
+// computed from `from`. Returns `from` unchanged if `to` is unreachable.
+func nextStepOn(g grid, from coord, to coord) coord {
+	dists := bfsDistances(g, from)
+	if dists[to.y][to.x] == infDistance {
+		return from
+	}
+
+	current := to
+	for dists[current.y][current.x] > 1 {
+		for _, n := range neighbors(g, current) {
+			if dists[n.y][n.x] == dists[current.y][current.x]-1 {
+				current = n
+				break
+			}
+		}
+	}
+	return current
+}
+
+// predictedTargetPath returns the cells a wandering minion will walk over
+// its remaining recallTime turns, assuming its target explorer holds its
+// current coord. Empty if the target explorer can't be found or the minion
+// already stands on it.
+func predictedTargetPath(w wanderer, explorers []explorer, g grid) []coord {
+	target, found := coord{}, false
+	for _, e := range explorers {
+		if e.id == w.target {
+			target = e.coord
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	path := make([]coord, 0, w.recallTime)
+	current := w.coord
+	for i := 0; i < w.recallTime && current != target; i++ {
+		current = nextStepOn(g, current, target)
+		path = append(path, current)
+	}
+	return path
+}
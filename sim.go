@@ -0,0 +1,172 @@
+package main
+
+import "math"
+
+// simMaxDepth bounds how many turns the minimax rollout looks ahead; beyond
+// this, wanderer targeting assumptions (and our ignorance of ally moves)
+// make deeper search not worth the cost.
+const simMaxDepth = 3
+
+// hitPenalty is the sanity cost of ending a turn on the same cell as a
+// wandering minion, dwarfing any loneliness penalty so the search always
+// steers away from a guaranteed hit.
+const hitPenalty = 1000.0
+
+// shelterApproachBonus rewards stepping onto a shelter cell, so the move
+// search itself closes the last step or two toward a shelter that's too far
+// for decideAbility's actionShelter to fire on directly.
+const shelterApproachBonus = 5.0
+
+// simState is the minimal board state the rollout needs to advance: the
+// grid never changes, explorers only move by our own simulated myMove
+// (allies are assumed to hold position, since we don't control them), and
+// wanderers/spawningMinions evolve per the game's minion AI.
+type simState struct {
+	g                grid
+	explorers        []explorer
+	myID             int
+	wanderers        []wanderer
+	spawningMinions  []spawningMinion
+	wandererLifeTime int
+	sanityLossLonely int
+	sanityLossGroup  int
+	dangers          [][]float64
+}
+
+func findExplorer(explorers []explorer, id int) explorer {
+	for _, e := range explorers {
+		if e.id == id {
+			return e
+		}
+	}
+	panic("explorer not found")
+}
+
+func explorerCoordsByID(explorers []explorer) map[int]coord {
+	res := make(map[int]coord, len(explorers))
+	for _, e := range explorers {
+		res[e.id] = e.coord
+	}
+	return res
+}
+
+// simulateTurn advances every wanderer and spawningMinion by one turn, with
+// our explorer moving to myMove and allies holding still. Each wandering
+// minion steps one cell toward its target explorer's current coord, via
+// nextStepOn so ties break exactly like the real BFS move selection, and
+// despawns once its recallTime runs out. Spawning minions count spawnTime
+// down and promote to stateWandering at 0.
+func simulateTurn(state simState, myMove coord) simState {
+	nextExplorers := make([]explorer, len(state.explorers))
+	for i, e := range state.explorers {
+		if e.id == state.myID {
+			e.coord = myMove
+		}
+		nextExplorers[i] = e
+	}
+	targetCoords := explorerCoordsByID(nextExplorers)
+
+	nextWanderers := make([]wanderer, 0, len(state.wanderers)+len(state.spawningMinions))
+	for _, w := range state.wanderers {
+		w.recallTime--
+		if w.recallTime <= 0 {
+			continue
+		}
+		target, ok := targetCoords[w.target]
+		if !ok {
+			target = w.coord
+		}
+		w.coord = nextStepOn(state.g, w.coord, target)
+		nextWanderers = append(nextWanderers, w)
+	}
+
+	nextSpawningMinions := make([]spawningMinion, 0, len(state.spawningMinions))
+	for _, s := range state.spawningMinions {
+		s.spawnTime--
+		if s.spawnTime <= 0 {
+			nextWanderers = append(nextWanderers, wanderer{s.id, s.coord, stateWandering, s.target, state.wandererLifeTime})
+			continue
+		}
+		nextSpawningMinions = append(nextSpawningMinions, s)
+	}
+
+	return simState{
+		g:                state.g,
+		explorers:        nextExplorers,
+		myID:             state.myID,
+		wanderers:        nextWanderers,
+		spawningMinions:  nextSpawningMinions,
+		wandererLifeTime: state.wandererLifeTime,
+		sanityLossLonely: state.sanityLossLonely,
+		sanityLossGroup:  state.sanityLossGroup,
+		dangers:          state.dangers,
+	}
+}
+
+// evaluate scores the cost of stepping to myMove from state: a heavy
+// penalty for landing on a wanderer (a hit, checked against `next` — the
+// wanderers' positions after they react to myMove, since a chasing wanderer
+// converges onto myMove the same turn we step into it), the danger map's
+// cost at myMove (which folds in predicted wanderer paths further out, so
+// the search also avoids a cell a wanderer will reach a turn or two from
+// now), a bonus for reaching a shelter cell, plus the turn's unavoidable
+// sanity loss, lonely or grouped depending on how close the nearest ally is.
+func evaluate(state simState, next simState, myMove coord) float64 {
+	cost := 0.0
+	for _, w := range next.wanderers {
+		if w.coord == myMove {
+			cost += hitPenalty
+		}
+	}
+
+	if state.dangers != nil {
+		cost += state.dangers[myMove.y][myMove.x]
+	}
+
+	if state.g[myMove.y][myMove.x] == cellShelter {
+		cost -= shelterApproachBonus
+	}
+
+	allyCoords := make([]coord, 0, len(state.explorers))
+	for _, e := range state.explorers {
+		if e.id != state.myID {
+			allyCoords = append(allyCoords, e.coord)
+		}
+	}
+	if len(allyCoords) > 0 && minBFSDistanceTo(state.g, myMove, allyCoords) <= 1 {
+		cost += float64(state.sanityLossGroup)
+	} else {
+		cost += float64(state.sanityLossLonely)
+	}
+	return cost
+}
+
+// searchBestMove runs a depth-limited minimax over the 5 candidate moves (4
+// directions + WAIT, WAIT encoded as holding the current coord), simulating
+// the wanderers' deterministic response at each level and summing evaluate
+// along the rollout. Returns the first move of the best-scoring rollout.
+func searchBestMove(state simState, depth int) coord {
+	move, _ := searchBestMoveScore(state, depth)
+	return move
+}
+
+func searchBestMoveScore(state simState, depth int) (coord, float64) {
+	me := findExplorer(state.explorers, state.myID)
+	candidates := append(neighbors(state.g, me.coord), me.coord)
+
+	bestMove := me.coord
+	bestScore := math.Inf(1)
+	for _, c := range candidates {
+		next := simulateTurn(state, c)
+		score := evaluate(state, next, c)
+		if depth > 1 {
+			_, restScore := searchBestMoveScore(next, depth-1)
+			score += restScore
+		}
+		if score < bestScore {
+			bestScore = score
+			bestMove = c
+		}
+	}
+	return bestMove, bestScore
+}